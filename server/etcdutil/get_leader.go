@@ -3,17 +3,84 @@
 package etcdutil
 
 import (
+	"fmt"
+
+	"go.etcd.io/etcd/client/pkg/v3/types"
 	"go.etcd.io/etcd/server/v3/etcdserver"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/membership"
 )
 
+// EtcdMember mirrors the subset of membership.Member fields that callers
+// outside this package are allowed to depend on.
+type EtcdMember struct {
+	ID         uint64
+	Name       string
+	PeerURLs   []string
+	ClientURLs []string
+	IsLearner  bool
+}
+
 type EtcdLeaderGetter interface {
 	EtcdLeaderID() (uint64, error)
 }
 
+// EtcdCluster mirrors the subset of etcdserver/api.Cluster that callers
+// outside this package need to render cluster topology or reject stale
+// member lists.
+type EtcdCluster interface {
+	// ClusterID returns the ID of the etcd cluster this member belongs to.
+	ClusterID() (uint64, error)
+	// Members returns the full membership list of the etcd cluster.
+	Members() ([]EtcdMember, error)
+	// Member returns the member with the given ID, or an error if it is not
+	// part of the cluster.
+	Member(id uint64) (*EtcdMember, error)
+	// Self returns the member info of the local etcd server.
+	Self() (*EtcdMember, error)
+}
+
 type LeaderGetterWrapper struct {
 	Server *etcdserver.EtcdServer
+
+	leaderWatch leaderWatcher
 }
 
 func (w *LeaderGetterWrapper) EtcdLeaderID() (uint64, error) {
 	return w.Server.Lead(), nil
 }
+
+func (w *LeaderGetterWrapper) ClusterID() (uint64, error) {
+	return uint64(w.Server.Cluster().ID()), nil
+}
+
+func (w *LeaderGetterWrapper) Members() ([]EtcdMember, error) {
+	members := w.Server.Cluster().Members()
+	ret := make([]EtcdMember, 0, len(members))
+	for _, m := range members {
+		ret = append(ret, toEtcdMember(m))
+	}
+	return ret, nil
+}
+
+func (w *LeaderGetterWrapper) Member(id uint64) (*EtcdMember, error) {
+	m := w.Server.Cluster().Member(types.ID(id))
+	if m == nil {
+		return nil, fmt.Errorf("member %d not found in cluster", id)
+	}
+	member := toEtcdMember(m)
+	return &member, nil
+}
+
+func (w *LeaderGetterWrapper) Self() (*EtcdMember, error) {
+	return w.Member(uint64(w.Server.ID()))
+}
+
+func toEtcdMember(m *membership.Member) EtcdMember {
+	return EtcdMember{
+		ID:         uint64(m.ID),
+		Name:       m.Name,
+		PeerURLs:   m.PeerURLs,
+		ClientURLs: m.ClientURLs,
+		IsLearner:  m.IsLearner,
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2022 CeresDB Project Authors. Licensed under Apache-2.0.
+
+package etcdutil
+
+import (
+	"reflect"
+	"testing"
+
+	"go.etcd.io/etcd/server/v3/etcdserver/api/membership"
+)
+
+func TestToEtcdMember(t *testing.T) {
+	m := &membership.Member{
+		ID: 0x1234,
+		RaftAttributes: membership.RaftAttributes{
+			PeerURLs:  []string{"http://peer:2380"},
+			IsLearner: true,
+		},
+		Attributes: membership.Attributes{
+			Name:       "node-1",
+			ClientURLs: []string{"http://client:2379"},
+		},
+	}
+
+	got := toEtcdMember(m)
+	want := EtcdMember{
+		ID:         0x1234,
+		Name:       "node-1",
+		PeerURLs:   []string{"http://peer:2380"},
+		ClientURLs: []string{"http://client:2379"},
+		IsLearner:  true,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("toEtcdMember() = %+v, want %+v", got, want)
+	}
+}
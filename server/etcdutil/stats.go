@@ -0,0 +1,133 @@
+// Copyright 2022 CeresDB Project Authors. Licensed under Apache-2.0.
+
+package etcdutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.etcd.io/etcd/server/v3/mvcc"
+)
+
+// SelfStats describes the local server's own raft state, the Go analogue of
+// etcdserver/api/v2stats.ServerStats.
+type SelfStats struct {
+	ID        string    `json:"id"`
+	State     string    `json:"state"`
+	StartTime time.Time `json:"startTime"`
+
+	RecvAppendRequestCnt uint64 `json:"recvAppendRequestCnt"`
+	SendAppendRequestCnt uint64 `json:"sendAppendRequestCnt"`
+}
+
+// FollowerStats describes one follower as seen from the current leader.
+type FollowerStats struct {
+	ID string
+
+	// LatencySeconds is the follower's average round-trip time, in seconds.
+	LatencySeconds float64
+
+	SuccessCnt uint64
+	FailCnt    uint64
+}
+
+// LeaderStats is only meaningful when the local server is the raft leader;
+// it is the Go analogue of etcdserver/api/v2stats.LeaderStats.
+type LeaderStats struct {
+	Leader    string
+	Followers []FollowerStats
+}
+
+// wireLeaderStats mirrors the on-the-wire shape of
+// etcdserver/api/v2stats.LeaderStats, where followers is a JSON object keyed
+// by follower ID rather than an array.
+type wireLeaderStats struct {
+	Leader    string `json:"leader"`
+	Followers map[string]struct {
+		Latency struct {
+			Average float64 `json:"average"`
+		} `json:"latency"`
+		Counts struct {
+			Success uint64 `json:"success"`
+			Fail    uint64 `json:"fail"`
+		} `json:"counts"`
+	} `json:"followers"`
+}
+
+// StoreStats is the Go analogue of etcdserver/api/v2stats.StoreStats, plus
+// the raft commit/apply indices operators need to diagnose lag.
+type StoreStats struct {
+	DBSizeBytes int64 `json:"dbSizeBytes"`
+	KeyCount    int64 `json:"keyCount"`
+
+	CommitIndex  uint64 `json:"commitIndex"`
+	AppliedIndex uint64 `json:"appliedIndex"`
+}
+
+// ClusterStatsGetter exposes the same self/leader/store statistics etcd's
+// own v2 stats handlers report, but as typed structs rather than raw JSON,
+// so HoraeMeta can diagnose raft lag or expose it through its own admin API
+// without re-parsing etcd's wire format.
+type ClusterStatsGetter interface {
+	SelfStats() (*SelfStats, error)
+	LeaderStats() (*LeaderStats, error)
+	// StoreStats additionally counts the keys currently in the store, which
+	// requires a context to bound that scan.
+	StoreStats(ctx context.Context) (*StoreStats, error)
+}
+
+func (w *LeaderGetterWrapper) SelfStats() (*SelfStats, error) {
+	stats := &SelfStats{}
+	if err := json.Unmarshal(w.Server.SelfStats(), stats); err != nil {
+		return nil, fmt.Errorf("unmarshal self stats: %w", err)
+	}
+	return stats, nil
+}
+
+func (w *LeaderGetterWrapper) LeaderStats() (*LeaderStats, error) {
+	raw := w.Server.LeaderStats()
+	if raw == nil {
+		return nil, fmt.Errorf("local server %s is not the raft leader", w.Server.ID())
+	}
+
+	wire := &wireLeaderStats{}
+	if err := json.Unmarshal(raw, wire); err != nil {
+		return nil, fmt.Errorf("unmarshal leader stats: %w", err)
+	}
+
+	stats := &LeaderStats{
+		Leader:    wire.Leader,
+		Followers: make([]FollowerStats, 0, len(wire.Followers)),
+	}
+	for id, f := range wire.Followers {
+		stats.Followers = append(stats.Followers, FollowerStats{
+			ID:             id,
+			// etcd reports follower latency in milliseconds.
+			LatencySeconds: f.Latency.Average / 1000,
+			SuccessCnt:     f.Counts.Success,
+			FailCnt:        f.Counts.Fail,
+		})
+	}
+	return stats, nil
+}
+
+func (w *LeaderGetterWrapper) StoreStats(ctx context.Context) (*StoreStats, error) {
+	stats := &StoreStats{}
+	if err := json.Unmarshal(w.Server.StoreStats(), stats); err != nil {
+		return nil, fmt.Errorf("unmarshal store stats: %w", err)
+	}
+
+	stats.AppliedIndex = w.Server.AppliedIndex()
+	stats.CommitIndex = w.Server.CommittedIndex()
+	stats.DBSizeBytes = w.Server.Backend().Size()
+
+	rr, err := w.Server.KV().Range(ctx, []byte{0}, []byte{}, mvcc.RangeOptions{Count: true})
+	if err != nil {
+		return nil, fmt.Errorf("count keys: %w", err)
+	}
+	stats.KeyCount = int64(rr.Count)
+
+	return stats, nil
+}
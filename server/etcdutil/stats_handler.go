@@ -0,0 +1,46 @@
+// Copyright 2022 CeresDB Project Authors. Licensed under Apache-2.0.
+
+package etcdutil
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// statsResponse is the payload served at the /api/v1/etcd/stats endpoint.
+type statsResponse struct {
+	Self  *SelfStats  `json:"self"`
+	Store *StoreStats `json:"store"`
+	// Leader is omitted when the local server is not the raft leader.
+	Leader *LeaderStats `json:"leader,omitempty"`
+}
+
+// NewStatsHandler returns an http.Handler that serves the local server's
+// self, store, and (when applicable) leader stats as JSON, so operators can
+// diagnose etcd raft lag without SSH'ing to the node. HoraeMeta's HTTP
+// router should mount it at /api/v1/etcd/stats.
+func NewStatsHandler(g ClusterStatsGetter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		self, err := g.SelfStats()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		store, err := g.StoreStats(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		resp := statsResponse{Self: self, Store: store}
+		if leader, err := g.LeaderStats(); err == nil {
+			resp.Leader = leader
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,51 @@
+// Copyright 2022 CeresDB Project Authors. Licensed under Apache-2.0.
+
+package etcdutil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+type fakeClusterStatsGetter struct {
+	self      *SelfStats
+	store     *StoreStats
+	leader    *LeaderStats
+	leaderErr error
+}
+
+func (f *fakeClusterStatsGetter) SelfStats() (*SelfStats, error) { return f.self, nil }
+func (f *fakeClusterStatsGetter) StoreStats(ctx context.Context) (*StoreStats, error) {
+	return f.store, nil
+}
+func (f *fakeClusterStatsGetter) LeaderStats() (*LeaderStats, error) {
+	return f.leader, f.leaderErr
+}
+
+var _ ClusterStatsGetter = (*fakeClusterStatsGetter)(nil)
+
+func TestStatsHandlerOmitsLeaderWhenNotLeader(t *testing.T) {
+	g := &fakeClusterStatsGetter{
+		self:      &SelfStats{ID: "1"},
+		store:     &StoreStats{KeyCount: 3},
+		leaderErr: errors.New("not the raft leader"),
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/v1/etcd/stats", nil)
+	NewStatsHandler(g).ServeHTTP(rec, req)
+
+	var resp statsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if resp.Self.ID != "1" || resp.Store.KeyCount != 3 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if resp.Leader != nil {
+		t.Fatalf("expected no leader stats, got %+v", resp.Leader)
+	}
+}
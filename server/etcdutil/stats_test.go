@@ -0,0 +1,43 @@
+// Copyright 2022 CeresDB Project Authors. Licensed under Apache-2.0.
+
+package etcdutil
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// sampleLeaderStatsJSON is a trimmed-down version of the payload
+// etcdserver/api/v2stats.LeaderStats actually serializes to: followers is a
+// JSON object keyed by follower ID, not an array.
+const sampleLeaderStatsJSON = `{
+	"leader": "1",
+	"followers": {
+		"2": {
+			"latency": {"average": 1.5},
+			"counts": {"success": 10, "fail": 1}
+		}
+	}
+}`
+
+func TestWireLeaderStatsDecodesFollowerMap(t *testing.T) {
+	wire := &wireLeaderStats{}
+	if err := json.Unmarshal([]byte(sampleLeaderStatsJSON), wire); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if wire.Leader != "1" {
+		t.Fatalf("Leader = %q, want %q", wire.Leader, "1")
+	}
+
+	follower, ok := wire.Followers["2"]
+	if !ok {
+		t.Fatalf("expected follower %q in %+v", "2", wire.Followers)
+	}
+	if follower.Latency.Average != 1.5 {
+		t.Fatalf("Latency.Average = %v, want 1.5", follower.Latency.Average)
+	}
+	if follower.Counts.Success != 10 || follower.Counts.Fail != 1 {
+		t.Fatalf("Counts = %+v, want {Success:10 Fail:1}", follower.Counts)
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2022 CeresDB Project Authors. Licensed under Apache-2.0.
+
+package etcdutil
+
+import (
+	"fmt"
+
+	"github.com/coreos/go-semver/semver"
+	"go.etcd.io/etcd/api/v3/version"
+)
+
+// MinClusterVersion is the lowest etcd cluster protocol version HoraeMeta
+// supports. A rolling etcd upgrade can leave a member running an older
+// protocol than the rest of the cluster; starting HoraeMeta against such a
+// cluster risks silent data corruption, so CheckCompatible refuses to run in
+// that case.
+var MinClusterVersion = semver.Must(semver.NewVersion("3.5.0"))
+
+// VersionGetter mirrors etcd's own version.Versions struct: the protocol
+// version negotiated by the cluster as a whole, and the version of the
+// locally embedded server binary.
+type VersionGetter interface {
+	// ClusterVersion returns the version of the storage protocol negotiated
+	// by the etcd cluster, which may lag the server binary's own version
+	// during a rolling upgrade.
+	ClusterVersion() (*semver.Version, error)
+	// ServerVersion returns the version of the locally embedded etcd
+	// server binary.
+	ServerVersion() (*semver.Version, error)
+}
+
+func (w *LeaderGetterWrapper) ClusterVersion() (*semver.Version, error) {
+	v := w.Server.Cluster().Version()
+	if v == nil {
+		return nil, fmt.Errorf("cluster version is not yet known")
+	}
+	return v, nil
+}
+
+func (w *LeaderGetterWrapper) ServerVersion() (*semver.Version, error) {
+	return semver.New(version.Version), nil
+}
+
+// CheckCompatible refuses to let HoraeMeta start against an etcd cluster
+// whose negotiated protocol version is below MinClusterVersion, guarding
+// against the case where HoraeMeta has been upgraded but a rolling etcd
+// cluster still has a member speaking an incompatible protocol.
+func CheckCompatible(g VersionGetter) error {
+	clusterVersion, err := g.ClusterVersion()
+	if err != nil {
+		return fmt.Errorf("get cluster version: %w", err)
+	}
+	if clusterVersion.LessThan(*MinClusterVersion) {
+		return fmt.Errorf("etcd cluster version %s is below the minimum supported version %s", clusterVersion, MinClusterVersion)
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+// Copyright 2022 CeresDB Project Authors. Licensed under Apache-2.0.
+
+package etcdutil
+
+import (
+	"testing"
+
+	"github.com/coreos/go-semver/semver"
+)
+
+// fakeVersionGetter implements VersionGetter with a configurable cluster
+// version.
+type fakeVersionGetter struct {
+	clusterVersion *semver.Version
+	clusterErr     error
+}
+
+func (f *fakeVersionGetter) ServerVersion() (*semver.Version, error) {
+	return f.clusterVersion, nil
+}
+func (f *fakeVersionGetter) ClusterVersion() (*semver.Version, error) {
+	return f.clusterVersion, f.clusterErr
+}
+
+var _ VersionGetter = (*fakeVersionGetter)(nil)
+
+func TestCheckCompatibleAcceptsVersionAtMinimum(t *testing.T) {
+	g := &fakeVersionGetter{clusterVersion: MinClusterVersion}
+	if err := CheckCompatible(g); err != nil {
+		t.Fatalf("CheckCompatible() = %v, want nil", err)
+	}
+}
+
+func TestCheckCompatibleRejectsVersionBelowMinimum(t *testing.T) {
+	g := &fakeVersionGetter{clusterVersion: semver.Must(semver.NewVersion("3.4.0"))}
+	if err := CheckCompatible(g); err == nil {
+		t.Fatal("CheckCompatible() = nil, want an error for a too-old cluster version")
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2022 CeresDB Project Authors. Licensed under Apache-2.0.
+
+package etcdutil
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// leaderChangeChanBufferSize bounds how many pending LeaderChange events a
+// subscriber may queue before new events are dropped for it. Subscribers are
+// expected to consume promptly; a slow subscriber should lose events rather
+// than block the fan-out goroutine.
+const leaderChangeChanBufferSize = 16
+
+// LeaderChange describes a single leader transition observed on the embedded
+// etcd server.
+type LeaderChange struct {
+	OldLeaderID uint64
+	NewLeaderID uint64
+	Term        uint64
+	Time        time.Time
+}
+
+// LeaderWatcher lets callers subscribe to leader-change notifications
+// instead of polling EtcdLeaderGetter.EtcdLeaderID.
+type LeaderWatcher interface {
+	// WatchLeader subscribes to leader-change notifications; the
+	// subscription ends when ctx is cancelled. Slow consumers may miss
+	// events rather than block other subscribers.
+	WatchLeader(ctx context.Context) (<-chan LeaderChange, error)
+}
+
+func (w *LeaderGetterWrapper) WatchLeader(ctx context.Context) (<-chan LeaderChange, error) {
+	w.leaderWatch.run(w)
+
+	ch := make(chan LeaderChange, leaderChangeChanBufferSize)
+	w.leaderWatch.subscribe(ctx, ch)
+	return ch, nil
+}
+
+// leaderWatcher fans out leader changes observed on the embedded etcd server
+// to any number of subscribers, dropping events for subscribers that fail to
+// keep up rather than blocking on them.
+type leaderWatcher struct {
+	startOnce sync.Once
+
+	mu   sync.Mutex
+	subs map[chan<- LeaderChange]struct{}
+}
+
+func (lw *leaderWatcher) subscribe(ctx context.Context, ch chan<- LeaderChange) {
+	lw.mu.Lock()
+	if lw.subs == nil {
+		lw.subs = make(map[chan<- LeaderChange]struct{})
+	}
+	lw.subs[ch] = struct{}{}
+	lw.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		lw.mu.Lock()
+		delete(lw.subs, ch)
+		close(ch)
+		lw.mu.Unlock()
+	}()
+}
+
+func (lw *leaderWatcher) broadcast(change LeaderChange) {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	for ch := range lw.subs {
+		select {
+		case ch <- change:
+		default:
+			// Slow consumer: drop the event rather than block the fan-out.
+		}
+	}
+}
+
+// run lazily starts the background goroutine that watches the server's
+// leader-change notifications for as long as the server is up, broadcasting
+// each transition to subscribers. It is safe to call repeatedly; only the
+// first call has an effect.
+func (lw *leaderWatcher) run(w *LeaderGetterWrapper) {
+	lw.startOnce.Do(func() {
+		go func() {
+			lastLeader := w.Server.Lead()
+			for {
+				select {
+				case <-w.Server.StopNotify():
+					return
+				case <-w.Server.LeaderChangedNotify():
+					newLeader := w.Server.Lead()
+					if newLeader == lastLeader {
+						continue
+					}
+					lw.broadcast(LeaderChange{
+						OldLeaderID: lastLeader,
+						NewLeaderID: newLeader,
+						Term:        w.Server.Term(),
+						Time:        time.Now(),
+					})
+					lastLeader = newLeader
+				}
+			}
+		}()
+	})
+}
@@ -0,0 +1,75 @@
+// Copyright 2022 CeresDB Project Authors. Licensed under Apache-2.0.
+
+package etcdutil
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLeaderWatcherBroadcast(t *testing.T) {
+	lw := &leaderWatcher{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := make(chan LeaderChange, 1)
+	lw.subscribe(ctx, ch)
+
+	want := LeaderChange{OldLeaderID: 1, NewLeaderID: 2, Term: 3}
+	lw.broadcast(want)
+
+	select {
+	case got := <-ch:
+		if got != want {
+			t.Fatalf("broadcast() = %+v, want %+v", got, want)
+		}
+	default:
+		t.Fatal("expected a subscriber to receive the broadcast change")
+	}
+}
+
+func TestLeaderWatcherBroadcastDropsForSlowSubscriber(t *testing.T) {
+	lw := &leaderWatcher{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := make(chan LeaderChange, 1)
+	lw.subscribe(ctx, ch)
+
+	// Fill the subscriber's buffer, then broadcast again: the second event
+	// must be dropped rather than block the fan-out.
+	lw.broadcast(LeaderChange{NewLeaderID: 1})
+	lw.broadcast(LeaderChange{NewLeaderID: 2})
+
+	got := <-ch
+	if got.NewLeaderID != 1 {
+		t.Fatalf("expected the first event to survive, got %+v", got)
+	}
+	select {
+	case extra := <-ch:
+		t.Fatalf("expected the second event to be dropped, got %+v", extra)
+	default:
+	}
+}
+
+func TestLeaderWatcherUnsubscribeClosesChannel(t *testing.T) {
+	lw := &leaderWatcher{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan LeaderChange, 1)
+	lw.subscribe(ctx, ch)
+
+	cancel()
+
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Fatal("expected channel to be closed, got a value instead")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for channel to close after context cancellation")
+	}
+}